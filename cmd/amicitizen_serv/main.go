@@ -0,0 +1,109 @@
+// Command amicitizen_serv serves the passport blacklist API, keeping its
+// dataset in sync with a remote source in the background.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/illabo/amicitizen_serv/internal/blacklist"
+	"github.com/illabo/amicitizen_serv/internal/config"
+	"github.com/illabo/amicitizen_serv/internal/httpapi"
+	"github.com/illabo/amicitizen_serv/internal/logging"
+	"github.com/illabo/amicitizen_serv/internal/updater"
+)
+
+// Exit codes distinguish which stage of startup failed, for scripts and
+// process supervisors that key off them.
+const (
+	exitConfigError = iota + 1
+	exitStoreError
+	exitListenError
+)
+
+// shutdownTimeout bounds how long in-flight HTTP requests get to finish
+// once a shutdown signal arrives. The wait for the updater to return is
+// not bounded by it.
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	cfgPath := flag.String("config", "config.toml", "custom path to config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*cfgPath)
+	if err != nil {
+		// cfg.Log isn't available yet, so there's no level/format to
+		// configure a logger from; fall back to slog's default handler
+		// rather than leaving this one call site on fmt.Println.
+		slog.Default().Error("failed to load config", slog.Any("error", err))
+		os.Exit(exitConfigError)
+	}
+
+	logger := logging.New(cfg.Log, os.Stdout)
+	slog.SetDefault(logger)
+
+	store, err := blacklist.Open(blacklist.Config{
+		Driver: cfg.Storage.Driver,
+		DSN:    cfg.Storage.DSN,
+	})
+	if err != nil {
+		logger.Error("failed to open blacklist store", slog.Any("error", err))
+		os.Exit(exitStoreError)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	upd := updater.New(cfg.RemoteFile, store, time.Duration(cfg.UpdateEvery)*time.Hour, logger)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		upd.Run(ctx)
+	}()
+
+	api := httpapi.New(store, upd, logger, cfg.Log.DebugPII)
+	srv := &http.Server{Addr: fmt.Sprintf(":%d", cfg.Port), Handler: api.Routes()}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("listening", slog.Int("port", cfg.Port))
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	var exitCode int
+	select {
+	case <-ctx.Done():
+		logger.Info("shutdown signal received")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error("error shutting down server", slog.Any("error", err))
+		}
+		cancel()
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("server stopped", slog.Any("error", err))
+			exitCode = exitListenError
+		}
+	}
+
+	// Wait for the updater to observe ctx cancellation and return, so it
+	// isn't mid-write when the store closes below.
+	stop()
+	wg.Wait()
+
+	if err := store.Close(); err != nil {
+		logger.Error("error closing blacklist store", slog.Any("error", err))
+	}
+
+	os.Exit(exitCode)
+}
@@ -0,0 +1,149 @@
+package blacklist
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	passportsBucket = "passports"
+	statusBucket    = "ustatus"
+	updatedKey      = "updated"
+	etagKey         = "etag"
+)
+
+// BoltStore is a BoltDB-backed Store. It is the original backend and
+// remains the default.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBolt opens (creating if necessary) the bolt database at path.
+func OpenBolt(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0644, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Close releases the underlying database file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// IsBlacklisted reports whether num is present in the blacklist.
+func (s *BoltStore) IsBlacklisted(ctx context.Context, num string) (bool, error) {
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(passportsBucket))
+		if b == nil {
+			return nil
+		}
+		v := b.Get([]byte(strings.TrimSpace(num)))
+		found = len(v) > 0
+		return nil
+	})
+	return found, err
+}
+
+// IsBlacklistedBatch reports blacklist membership for many numbers in a
+// single read transaction, rather than one transaction per number.
+func (s *BoltStore) IsBlacklistedBatch(ctx context.Context, nums []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(nums))
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(passportsBucket))
+		if b == nil {
+			return nil
+		}
+		for _, n := range nums {
+			v := b.Get([]byte(strings.TrimSpace(n)))
+			result[n] = len(v) > 0
+		}
+		return nil
+	})
+	return result, err
+}
+
+// PutBatch adds nums to the blacklist in a single transaction, skipping
+// entries already present.
+func (s *BoltStore) PutBatch(ctx context.Context, nums []string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(passportsBucket))
+		if err != nil {
+			return err
+		}
+		for _, n := range nums {
+			key := []byte(strings.TrimSpace(n))
+			if len(b.Get(key)) == 0 {
+				if err := b.Put(key, []byte("1")); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// GetLastUpdated returns the time of the last successful dataset update,
+// or the zero time if no update has completed yet.
+func (s *BoltStore) GetLastUpdated(ctx context.Context) (time.Time, error) {
+	var t time.Time
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(statusBucket))
+		if b == nil {
+			return nil
+		}
+		v := b.Get([]byte(updatedKey))
+		if len(v) == 0 {
+			return nil
+		}
+		parsed, err := time.Parse(time.RFC1123, string(v))
+		if err != nil {
+			return err
+		}
+		t = parsed
+		return nil
+	})
+	return t, err
+}
+
+// SetLastUpdated records t as the time of the last successful update.
+func (s *BoltStore) SetLastUpdated(ctx context.Context, t time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(statusBucket))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(updatedKey), []byte(t.Format(time.RFC1123)))
+	})
+}
+
+// GetETag returns the remote's ETag for the dataset currently stored, or
+// "" if no update has completed yet.
+func (s *BoltStore) GetETag(ctx context.Context) (string, error) {
+	var etag string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(statusBucket))
+		if b == nil {
+			return nil
+		}
+		etag = string(b.Get([]byte(etagKey)))
+		return nil
+	})
+	return etag, err
+}
+
+// SetETag records the remote's ETag for the dataset currently stored.
+func (s *BoltStore) SetETag(ctx context.Context, etag string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(statusBucket))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(etagKey), []byte(etag))
+	})
+}
@@ -0,0 +1,13 @@
+package blacklist
+
+import (
+	"fmt"
+
+	_ "github.com/lib/pq"
+)
+
+// OpenPostgres opens a Postgres-backed Store at dsn (a libpq connection
+// string or URL).
+func OpenPostgres(dsn string) (Store, error) {
+	return newSQLStore("postgres", dsn, func(n int) string { return fmt.Sprintf("$%d", n) })
+}
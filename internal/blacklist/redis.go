@@ -0,0 +1,107 @@
+package blacklist
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	redisSetKey     = "blacklist:passports"
+	redisLastUpdKey = "blacklist:last_updated"
+	redisETagKey    = "blacklist:etag"
+)
+
+// RedisStore is a Redis-backed Store. Blacklisted numbers are kept in a
+// set for constant-time membership checks without a transactional read.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// OpenRedis opens a Redis-backed Store using dsn as a redis:// URL.
+func OpenRedis(dsn string) (Store, error) {
+	opt, err := redis.ParseURL(dsn)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opt)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &RedisStore{client: client}, nil
+}
+
+// Close releases the underlying connection pool.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+// IsBlacklisted reports whether num is present in the blacklist.
+func (s *RedisStore) IsBlacklisted(ctx context.Context, num string) (bool, error) {
+	return s.client.SIsMember(ctx, redisSetKey, num).Result()
+}
+
+// IsBlacklistedBatch reports blacklist membership for many numbers with a
+// single SMISMEMBER call, rather than one round trip per number.
+func (s *RedisStore) IsBlacklistedBatch(ctx context.Context, nums []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(nums))
+	if len(nums) == 0 {
+		return result, nil
+	}
+	members := make([]interface{}, len(nums))
+	for i, n := range nums {
+		members[i] = n
+	}
+	found, err := s.client.SMIsMember(ctx, redisSetKey, members...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, n := range nums {
+		result[n] = found[i]
+	}
+	return result, nil
+}
+
+// PutBatch adds nums to the blacklist in a single SADD call.
+func (s *RedisStore) PutBatch(ctx context.Context, nums []string) error {
+	members := make([]interface{}, len(nums))
+	for i, n := range nums {
+		members[i] = n
+	}
+	return s.client.SAdd(ctx, redisSetKey, members...).Err()
+}
+
+// GetLastUpdated returns the time of the last successful dataset update,
+// or the zero time if no update has completed yet.
+func (s *RedisStore) GetLastUpdated(ctx context.Context) (time.Time, error) {
+	raw, err := s.client.Get(ctx, redisLastUpdKey).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC1123, raw)
+}
+
+// SetLastUpdated records t as the time of the last successful update.
+func (s *RedisStore) SetLastUpdated(ctx context.Context, t time.Time) error {
+	return s.client.Set(ctx, redisLastUpdKey, t.Format(time.RFC1123), 0).Err()
+}
+
+// GetETag returns the remote's ETag for the dataset currently stored, or
+// "" if no update has completed yet.
+func (s *RedisStore) GetETag(ctx context.Context) (string, error) {
+	etag, err := s.client.Get(ctx, redisETagKey).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return etag, err
+}
+
+// SetETag records the remote's ETag for the dataset currently stored.
+func (s *RedisStore) SetETag(ctx context.Context, etag string) error {
+	return s.client.Set(ctx, redisETagKey, etag, 0).Err()
+}
@@ -0,0 +1,153 @@
+package blacklist
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sqlStore is a database/sql-backed Store shared by the SQLite and
+// Postgres backends; only the driver name and placeholder style differ
+// between them.
+type sqlStore struct {
+	db        *sql.DB
+	placehold func(n int) string // e.g. "?" for sqlite, "$1" for postgres
+}
+
+const (
+	sqlCreatePassports = `CREATE TABLE IF NOT EXISTS passports (number TEXT PRIMARY KEY)`
+	sqlCreateMeta      = `CREATE TABLE IF NOT EXISTS updater_meta (key TEXT PRIMARY KEY, value TEXT NOT NULL)`
+)
+
+func newSQLStore(driver, dsn string, placehold func(n int) string) (*sqlStore, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err := db.Exec(sqlCreatePassports); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err := db.Exec(sqlCreateMeta); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqlStore{db: db, placehold: placehold}, nil
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *sqlStore) IsBlacklisted(ctx context.Context, num string) (bool, error) {
+	query := fmt.Sprintf(`SELECT 1 FROM passports WHERE number = %s`, s.placehold(1))
+	var dummy int
+	err := s.db.QueryRowContext(ctx, query, num).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// IsBlacklistedBatch reports blacklist membership for many numbers with a
+// single SELECT ... IN query, rather than one query per number.
+func (s *sqlStore) IsBlacklistedBatch(ctx context.Context, nums []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(nums))
+	if len(nums) == 0 {
+		return result, nil
+	}
+	placeholders := make([]string, len(nums))
+	args := make([]interface{}, len(nums))
+	for i, n := range nums {
+		placeholders[i] = s.placehold(i + 1)
+		args[i] = n
+		result[n] = false
+	}
+	query := fmt.Sprintf(`SELECT number FROM passports WHERE number IN (%s)`, strings.Join(placeholders, ", "))
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var num string
+		if err := rows.Scan(&num); err != nil {
+			return nil, err
+		}
+		result[num] = true
+	}
+	return result, rows.Err()
+}
+
+func (s *sqlStore) PutBatch(ctx context.Context, nums []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	upsert := fmt.Sprintf(`INSERT INTO passports (number) VALUES (%s) ON CONFLICT (number) DO NOTHING`, s.placehold(1))
+	stmt, err := tx.PrepareContext(ctx, upsert)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, n := range nums {
+		if _, err := stmt.ExecContext(ctx, n); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStore) GetLastUpdated(ctx context.Context) (time.Time, error) {
+	query := fmt.Sprintf(`SELECT value FROM updater_meta WHERE key = %s`, s.placehold(1))
+	var raw string
+	err := s.db.QueryRowContext(ctx, query, updatedKey).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC1123, raw)
+}
+
+func (s *sqlStore) SetLastUpdated(ctx context.Context, t time.Time) error {
+	return s.setMeta(ctx, updatedKey, t.Format(time.RFC1123))
+}
+
+// GetETag returns the remote's ETag for the dataset currently stored, or
+// "" if no update has completed yet.
+func (s *sqlStore) GetETag(ctx context.Context) (string, error) {
+	query := fmt.Sprintf(`SELECT value FROM updater_meta WHERE key = %s`, s.placehold(1))
+	var etag string
+	err := s.db.QueryRowContext(ctx, query, etagKey).Scan(&etag)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return etag, err
+}
+
+// SetETag records the remote's ETag for the dataset currently stored.
+func (s *sqlStore) SetETag(ctx context.Context, etag string) error {
+	return s.setMeta(ctx, etagKey, etag)
+}
+
+func (s *sqlStore) setMeta(ctx context.Context, key, value string) error {
+	query := fmt.Sprintf(
+		`INSERT INTO updater_meta (key, value) VALUES (%s, %s) ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+		s.placehold(1), s.placehold(2))
+	_, err := s.db.ExecContext(ctx, query, key, value)
+	return err
+}
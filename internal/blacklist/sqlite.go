@@ -0,0 +1,10 @@
+package blacklist
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// OpenSQLite opens a SQLite-backed Store at dsn (typically a file path).
+func OpenSQLite(dsn string) (Store, error) {
+	return newSQLStore("sqlite3", dsn, func(n int) string { return "?" })
+}
@@ -0,0 +1,67 @@
+// Package blacklist stores the set of blacklisted passport numbers
+// behind a Store interface, so the backing database can be swapped
+// without touching the rest of the service.
+package blacklist
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Store is implemented by every supported backend for the passport
+// blacklist.
+type Store interface {
+	// IsBlacklisted reports whether num is present in the blacklist.
+	IsBlacklisted(ctx context.Context, num string) (bool, error)
+	// IsBlacklistedBatch reports blacklist membership for many numbers
+	// at once, using a single read rather than one per number.
+	IsBlacklistedBatch(ctx context.Context, nums []string) (map[string]bool, error)
+	// PutBatch adds nums to the blacklist, skipping entries already
+	// present.
+	PutBatch(ctx context.Context, nums []string) error
+	// GetLastUpdated returns the Last-Modified time reported by the
+	// remote source for the dataset currently stored, or the zero time
+	// if no update has completed yet.
+	GetLastUpdated(ctx context.Context) (time.Time, error)
+	// SetLastUpdated records the remote's Last-Modified time for the
+	// dataset currently stored.
+	SetLastUpdated(ctx context.Context, t time.Time) error
+	// GetETag returns the ETag reported by the remote source for the
+	// dataset currently stored, or "" if no update has completed yet.
+	GetETag(ctx context.Context) (string, error)
+	// SetETag records the remote's ETag for the dataset currently
+	// stored.
+	SetETag(ctx context.Context, etag string) error
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// Config selects and configures a storage backend, read from the
+// [storage] section of config.toml.
+type Config struct {
+	Driver string // "bolt" (default), "sqlite", "postgres" or "redis"
+	DSN    string
+}
+
+// Open opens the backend selected by cfg.Driver. An empty driver falls
+// back to the original BoltDB-backed store for compatibility with
+// configs predating the [storage] section.
+func Open(cfg Config) (Store, error) {
+	switch cfg.Driver {
+	case "", "bolt", "boltdb":
+		path := cfg.DSN
+		if path == "" {
+			path = "db/data.db"
+		}
+		return OpenBolt(path)
+	case "sqlite", "sqlite3":
+		return OpenSQLite(cfg.DSN)
+	case "postgres", "postgresql":
+		return OpenPostgres(cfg.DSN)
+	case "redis":
+		return OpenRedis(cfg.DSN)
+	default:
+		return nil, fmt.Errorf("blacklist: unknown storage driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,33 @@
+// Package config loads the service's TOML configuration file.
+package config
+
+import "github.com/BurntSushi/toml"
+
+// Config holds the settings read from config.toml.
+type Config struct {
+	RemoteFile  string
+	Port        int
+	UpdateEvery int
+	Storage     StorageConfig `toml:"storage"`
+	Log         LogConfig     `toml:"log"`
+}
+
+// StorageConfig selects and configures the blacklist storage backend.
+type StorageConfig struct {
+	Driver string // "bolt" (default), "sqlite", "postgres" or "redis"
+	DSN    string
+}
+
+// LogConfig selects the logger's level, output format and PII handling.
+type LogConfig struct {
+	Level    string // "debug", "info" (default), "warn" or "error"
+	Format   string // "json" (default) or "text"
+	DebugPII bool   `toml:"debug_pii"` // log raw passport numbers instead of a hash
+}
+
+// Load reads and decodes the TOML file at path.
+func Load(path string) (Config, error) {
+	var cfg Config
+	_, err := toml.DecodeFile(path, &cfg)
+	return cfg, err
+}
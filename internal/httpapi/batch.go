@@ -0,0 +1,112 @@
+package httpapi
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/illabo/amicitizen_serv/internal/logging"
+	"github.com/illabo/amicitizen_serv/internal/metrics"
+)
+
+// checkResult is the per-number outcome returned by /check/batch.
+type checkResult struct {
+	Number string `json:"number"`
+	Valid  bool   `json:"valid"`
+}
+
+// handleCheckBatch checks many passport numbers in one request, either a
+// JSON array (`["123456789", ...]`) or NDJSON (one number per line,
+// matching the on-disk format the updater ingests). It looks them up in
+// a single batched read and streams a result per number as it writes the
+// response, in the same format it was given.
+func (a *API) handleCheckBatch(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(res, "Only POST accepted", http.StatusBadRequest)
+		return
+	}
+
+	nums, ndjson, err := decodeBatchInput(req.Body)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	toLookup := make([]string, 0, len(nums))
+	for _, raw := range nums {
+		if num, err := validatePassportNumber(raw); err == nil {
+			toLookup = append(toLookup, num)
+		}
+	}
+	start := time.Now()
+	blacklisted, err := a.blacklist.IsBlacklistedBatch(req.Context(), toLookup)
+	metrics.LookupDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	metrics.LookupsTotal.Add(float64(len(toLookup)))
+	hits := 0
+	for _, b := range blacklisted {
+		if b {
+			hits++
+		}
+	}
+	metrics.BlacklistedTotal.Add(float64(hits))
+	logging.FromContext(req.Context()).Info("checked passport batch",
+		slog.Int("requested", len(nums)), slog.Int("looked_up", len(toLookup)))
+
+	flusher, _ := res.(http.Flusher)
+	enc := json.NewEncoder(res)
+	if ndjson {
+		res.Header().Set("Content-Type", "application/x-ndjson")
+	} else {
+		res.Header().Set("Content-Type", "application/json")
+		res.Write([]byte("["))
+	}
+	for i, raw := range nums {
+		num, formatErr := validatePassportNumber(raw)
+		result := checkResult{Number: raw, Valid: formatErr == nil && !blacklisted[num]}
+		if !ndjson && i > 0 {
+			res.Write([]byte(","))
+		}
+		enc.Encode(result)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	if !ndjson {
+		res.Write([]byte("]"))
+	}
+}
+
+// decodeBatchInput reads either a JSON array of passport numbers or
+// NDJSON (one per line) from body, detecting the format from its first
+// byte.
+func decodeBatchInput(body io.Reader) (nums []string, ndjson bool, err error) {
+	br := bufio.NewReader(body)
+	first, err := br.Peek(1)
+	if err != nil {
+		return nil, false, err
+	}
+	if first[0] == '[' {
+		var arr []string
+		if err := json.NewDecoder(br).Decode(&arr); err != nil {
+			return nil, false, err
+		}
+		return arr, false, nil
+	}
+
+	scanner := bufio.NewScanner(br)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			nums = append(nums, line)
+		}
+	}
+	return nums, true, scanner.Err()
+}
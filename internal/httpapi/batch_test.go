@@ -0,0 +1,84 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBatchInputJSONArray(t *testing.T) {
+	nums, ndjson, err := decodeBatchInput(strings.NewReader(`["123456789", "987654321"]`))
+	if err != nil {
+		t.Fatalf("decodeBatchInput returned error: %v", err)
+	}
+	if ndjson {
+		t.Fatalf("decodeBatchInput reported ndjson for a JSON array input")
+	}
+	want := []string{"123456789", "987654321"}
+	if !reflect.DeepEqual(nums, want) {
+		t.Fatalf("decodeBatchInput nums = %v, want %v", nums, want)
+	}
+}
+
+func TestDecodeBatchInputNDJSON(t *testing.T) {
+	nums, ndjson, err := decodeBatchInput(strings.NewReader("123456789\n987654321\n\n555555555\n"))
+	if err != nil {
+		t.Fatalf("decodeBatchInput returned error: %v", err)
+	}
+	if !ndjson {
+		t.Fatalf("decodeBatchInput did not report ndjson for line-delimited input")
+	}
+	want := []string{"123456789", "987654321", "555555555"}
+	if !reflect.DeepEqual(nums, want) {
+		t.Fatalf("decodeBatchInput nums = %v, want %v", nums, want)
+	}
+}
+
+func TestDecodeBatchInputEmpty(t *testing.T) {
+	_, _, err := decodeBatchInput(strings.NewReader(""))
+	if err == nil {
+		t.Fatalf("decodeBatchInput(\"\") = nil error, want an error")
+	}
+}
+
+func TestHandleCheckBatch(t *testing.T) {
+	bl := &fakeBlacklist{blacklisted: map[string]bool{"123456789": true}}
+	a := New(bl, &fakeUpdater{}, nil, false)
+
+	body := `["123456789", "987654321", "bad"]`
+	req := httptest.NewRequest(http.MethodPost, "/check/batch", strings.NewReader(body))
+	res := httptest.NewRecorder()
+
+	a.handleCheckBatch(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", res.Code, http.StatusOK)
+	}
+	var got []checkResult
+	if err := json.Unmarshal(res.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response body %q: %v", res.Body.String(), err)
+	}
+	want := []checkResult{
+		{Number: "123456789", Valid: false},
+		{Number: "987654321", Valid: true},
+		{Number: "bad", Valid: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("results = %+v, want %+v", got, want)
+	}
+}
+
+func TestHandleCheckBatchRejectsNonPost(t *testing.T) {
+	a := New(&fakeBlacklist{}, &fakeUpdater{}, nil, false)
+	req := httptest.NewRequest(http.MethodGet, "/check/batch", nil)
+	res := httptest.NewRecorder()
+
+	a.handleCheckBatch(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", res.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,54 @@
+package httpapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/illabo/amicitizen_serv/internal/updater"
+)
+
+// fakeBlacklist is an in-memory Blacklist for handler tests.
+type fakeBlacklist struct {
+	blacklisted map[string]bool
+	err         error
+	lastUpdated time.Time
+	lastUpdErr  error
+}
+
+func (f *fakeBlacklist) IsBlacklisted(ctx context.Context, num string) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.blacklisted[num], nil
+}
+
+func (f *fakeBlacklist) IsBlacklistedBatch(ctx context.Context, nums []string) (map[string]bool, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := make(map[string]bool, len(nums))
+	for _, num := range nums {
+		result[num] = f.blacklisted[num]
+	}
+	return result, nil
+}
+
+func (f *fakeBlacklist) GetLastUpdated(ctx context.Context) (time.Time, error) {
+	return f.lastUpdated, f.lastUpdErr
+}
+
+// fakeUpdater is a no-op Updater for handler tests that don't exercise
+// update scheduling itself.
+type fakeUpdater struct {
+	status    updater.Status
+	triggered bool
+}
+
+func (f *fakeUpdater) Status() updater.Status { return f.status }
+
+func (f *fakeUpdater) Trigger() { f.triggered = true }
+
+func (f *fakeUpdater) Subscribe() (<-chan updater.Event, func()) {
+	ch := make(chan updater.Event)
+	return ch, func() {}
+}
@@ -0,0 +1,126 @@
+// Package httpapi wires the blacklist store and updater into HTTP
+// handlers. Handlers depend on narrow interfaces rather than concrete
+// types so they can be tested in isolation.
+package httpapi
+
+import (
+	"context"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/illabo/amicitizen_serv/internal/logging"
+	"github.com/illabo/amicitizen_serv/internal/metrics"
+	"github.com/illabo/amicitizen_serv/internal/updater"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Blacklist is the subset of blacklist.Store the API needs to answer
+// lookups and report readiness.
+type Blacklist interface {
+	IsBlacklisted(ctx context.Context, num string) (bool, error)
+	IsBlacklistedBatch(ctx context.Context, nums []string) (map[string]bool, error)
+	GetLastUpdated(ctx context.Context) (time.Time, error)
+}
+
+// Updater is the subset of updater.Updater the API needs to report and
+// kick off dataset refreshes.
+type Updater interface {
+	Status() updater.Status
+	Trigger()
+	Subscribe() (<-chan updater.Event, func())
+}
+
+// API holds the dependencies shared by the HTTP handlers.
+type API struct {
+	blacklist Blacklist
+	updater   Updater
+	logger    *slog.Logger
+	debugPII  bool
+}
+
+// New builds an API backed by the given blacklist and updater, logging
+// through logger. debugPII controls whether passport numbers are logged
+// in full or only as a redacted hash.
+func New(blacklist Blacklist, upd Updater, logger *slog.Logger, debugPII bool) *API {
+	return &API{blacklist: blacklist, updater: upd, logger: logger, debugPII: debugPII}
+}
+
+// Routes returns the mux serving the API's endpoints.
+func (a *API) Routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", a.withRequestLogger(a.handlePassportValid))
+	mux.HandleFunc("/update", a.withRequestLogger(a.handleKickstartUpdate))
+	mux.HandleFunc("/ws/status", a.withRequestLogger(a.handleWSStatus))
+	mux.HandleFunc("/check/batch", a.withRequestLogger(a.handleCheckBatch))
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", a.handleHealthz)
+	mux.HandleFunc("/readyz", a.handleReadyz)
+	return mux
+}
+
+func (a *API) handlePassportValid(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(res, "Only POST accepted", http.StatusBadRequest)
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	num, err := validatePassportNumber(string(body))
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	logger := logging.FromContext(req.Context())
+	logger.Debug("checking passport number", slog.String("number", logging.RedactNumber(num, a.debugPII)))
+
+	start := time.Now()
+	blacklisted, err := a.blacklist.IsBlacklisted(req.Context(), num)
+	metrics.LookupDuration.Observe(time.Since(start).Seconds())
+	metrics.LookupsTotal.Inc()
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if blacklisted {
+		metrics.BlacklistedTotal.Inc()
+		res.Write([]byte("invalid"))
+		return
+	}
+	res.Write([]byte("valid"))
+}
+
+func (a *API) handleKickstartUpdate(res http.ResponseWriter, req *http.Request) {
+	switch a.updater.Status() {
+	case updater.Processing:
+		res.Write([]byte("Update is already in progress"))
+		return
+	default:
+		a.updater.Trigger()
+		res.Write([]byte("Checking remote for updated dataset"))
+	}
+}
+
+// handleHealthz reports that the process is alive.
+func (a *API) handleHealthz(res http.ResponseWriter, req *http.Request) {
+	res.Write([]byte("ok"))
+}
+
+// handleReadyz reports readiness: the store must be reachable and at
+// least one dataset update must have completed.
+func (a *API) handleReadyz(res http.ResponseWriter, req *http.Request) {
+	lastUpdated, err := a.blacklist.GetLastUpdated(req.Context())
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if lastUpdated.IsZero() {
+		http.Error(res, "no successful update yet", http.StatusServiceUnavailable)
+		return
+	}
+	res.Write([]byte("ok"))
+}
@@ -0,0 +1,127 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/illabo/amicitizen_serv/internal/updater"
+)
+
+var errTestStore = errors.New("store unavailable")
+
+func TestHandlePassportValid(t *testing.T) {
+	cases := []struct {
+		name        string
+		method      string
+		body        string
+		blacklisted map[string]bool
+		wantCode    int
+		wantBody    string
+	}{
+		{name: "valid", method: http.MethodPost, body: "123456789", wantCode: http.StatusOK, wantBody: "valid"},
+		{name: "blacklisted", method: http.MethodPost, body: "123456789", blacklisted: map[string]bool{"123456789": true}, wantCode: http.StatusOK, wantBody: "invalid"},
+		{name: "bad format", method: http.MethodPost, body: "not-a-number", wantCode: http.StatusUnprocessableEntity},
+		{name: "wrong method", method: http.MethodGet, body: "123456789", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := New(&fakeBlacklist{blacklisted: tc.blacklisted}, &fakeUpdater{}, nil, false)
+			req := httptest.NewRequest(tc.method, "/", strings.NewReader(tc.body))
+			res := httptest.NewRecorder()
+
+			a.handlePassportValid(res, req)
+
+			if res.Code != tc.wantCode {
+				t.Fatalf("status = %d, want %d", res.Code, tc.wantCode)
+			}
+			if tc.wantBody != "" && strings.TrimSpace(res.Body.String()) != tc.wantBody {
+				t.Fatalf("body = %q, want %q", res.Body.String(), tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestHandleKickstartUpdate(t *testing.T) {
+	t.Run("triggers when idle", func(t *testing.T) {
+		upd := &fakeUpdater{status: updater.Ready}
+		a := New(&fakeBlacklist{}, upd, nil, false)
+		req := httptest.NewRequest(http.MethodGet, "/update", nil)
+		res := httptest.NewRecorder()
+
+		a.handleKickstartUpdate(res, req)
+
+		if !upd.triggered {
+			t.Fatalf("handleKickstartUpdate did not call Trigger")
+		}
+	})
+
+	t.Run("does not trigger while processing", func(t *testing.T) {
+		upd := &fakeUpdater{status: updater.Processing}
+		a := New(&fakeBlacklist{}, upd, nil, false)
+		req := httptest.NewRequest(http.MethodGet, "/update", nil)
+		res := httptest.NewRecorder()
+
+		a.handleKickstartUpdate(res, req)
+
+		if upd.triggered {
+			t.Fatalf("handleKickstartUpdate called Trigger while an update was already processing")
+		}
+	})
+}
+
+func TestHandleHealthz(t *testing.T) {
+	a := New(&fakeBlacklist{}, &fakeUpdater{}, nil, false)
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	res := httptest.NewRecorder()
+
+	a.handleHealthz(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", res.Code, http.StatusOK)
+	}
+}
+
+func TestHandleReadyz(t *testing.T) {
+	t.Run("not ready before any update", func(t *testing.T) {
+		a := New(&fakeBlacklist{}, &fakeUpdater{}, nil, false)
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		res := httptest.NewRecorder()
+
+		a.handleReadyz(res, req)
+
+		if res.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want %d", res.Code, http.StatusServiceUnavailable)
+		}
+	})
+
+	t.Run("ready once the store reports a last update", func(t *testing.T) {
+		bl := &fakeBlacklist{lastUpdated: time.Now()}
+		a := New(bl, &fakeUpdater{}, nil, false)
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		res := httptest.NewRecorder()
+
+		a.handleReadyz(res, req)
+
+		if res.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", res.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("unavailable when the store errors", func(t *testing.T) {
+		bl := &fakeBlacklist{lastUpdErr: errTestStore}
+		a := New(bl, &fakeUpdater{}, nil, false)
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		res := httptest.NewRecorder()
+
+		a.handleReadyz(res, req)
+
+		if res.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want %d", res.Code, http.StatusServiceUnavailable)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package httpapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+
+	"github.com/illabo/amicitizen_serv/internal/logging"
+)
+
+// withRequestLogger attaches a logger carrying a per-request correlation
+// ID to the request context, and logs the request's arrival before
+// handing it to next.
+func (a *API) withRequestLogger(next http.HandlerFunc) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		requestID := newRequestID()
+		logger := a.logger.With(slog.String("request_id", requestID))
+
+		logger.Info("request received", slog.String("method", req.Method), slog.String("path", req.URL.Path))
+		next(res, req.WithContext(logging.WithLogger(req.Context(), logger)))
+	}
+}
+
+func newRequestID() string {
+	var buf [4]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf[:])
+}
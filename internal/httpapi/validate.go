@@ -0,0 +1,26 @@
+package httpapi
+
+import (
+	"errors"
+	"strings"
+)
+
+// passportNumberLength is the expected length of a passport number once
+// whitespace has been trimmed.
+const passportNumberLength = 9
+
+// validatePassportNumber trims raw and checks it is a plausible passport
+// number: digits only, at the expected length. It is shared by the
+// single-number and batch endpoints so both reject the same inputs.
+func validatePassportNumber(raw string) (string, error) {
+	num := strings.TrimSpace(raw)
+	if len(num) != passportNumberLength {
+		return "", errors.New("passport number must be 9 digits")
+	}
+	for _, r := range num {
+		if r < '0' || r > '9' {
+			return "", errors.New("passport number must be digits only")
+		}
+	}
+	return num, nil
+}
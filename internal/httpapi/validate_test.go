@@ -0,0 +1,37 @@
+package httpapi
+
+import "testing"
+
+func TestValidatePassportNumber(t *testing.T) {
+	cases := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{name: "valid", raw: "123456789", want: "123456789"},
+		{name: "trims whitespace", raw: "  123456789\n", want: "123456789"},
+		{name: "too short", raw: "12345678", wantErr: true},
+		{name: "too long", raw: "1234567890", wantErr: true},
+		{name: "non digit", raw: "12345678a", wantErr: true},
+		{name: "empty", raw: "", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := validatePassportNumber(tc.raw)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("validatePassportNumber(%q) = %q, nil; want error", tc.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validatePassportNumber(%q) unexpected error: %v", tc.raw, err)
+			}
+			if got != tc.want {
+				t.Fatalf("validatePassportNumber(%q) = %q, want %q", tc.raw, got, tc.want)
+			}
+		})
+	}
+}
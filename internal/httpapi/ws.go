@@ -0,0 +1,49 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+var wsUpgrader = websocket.Upgrader{
+	// Progress dashboards are read by operators on the same network as
+	// the service; there's no cross-origin credential to protect here.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// handleWSStatus streams updater.Event values as JSON for as long as the
+// client stays connected: status transitions, download/ingest progress
+// ticks, and the last-updated timestamp.
+func (a *API) handleWSStatus(res http.ResponseWriter, req *http.Request) {
+	conn, err := wsUpgrader.Upgrade(res, req, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	events, cancel := a.updater.Subscribe()
+	defer cancel()
+
+	// Detect the client going away; gorilla gives no other signal for it.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case e := <-events:
+			if err := conn.WriteJSON(e); err != nil {
+				return
+			}
+		}
+	}
+}
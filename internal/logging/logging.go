@@ -0,0 +1,68 @@
+// Package logging configures the service's structured logger and keeps
+// passport numbers out of log output unless explicitly opted into.
+package logging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+
+	"github.com/illabo/amicitizen_serv/internal/config"
+)
+
+// New builds a slog.Logger configured by cfg, writing to w.
+func New(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLevel(cfg.Level)}
+	var handler slog.Handler
+	if cfg.Format == "text" {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(handler)
+}
+
+func parseLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// RedactNumber returns num unchanged when debugPII is true; otherwise it
+// returns a short hash, so passport numbers never reach log output
+// unless an operator has explicitly opted in via log.debug_pii.
+func RedactNumber(num string, debugPII bool) string {
+	if debugPII {
+		return num
+	}
+	sum := sha256.Sum256([]byte(num))
+	return hex.EncodeToString(sum[:6])
+}
+
+type ctxKey int
+
+const loggerKey ctxKey = iota
+
+// WithLogger returns a context carrying logger, retrievable with
+// FromContext.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// FromContext returns the logger attached to ctx by WithLogger, or
+// slog.Default() if none was attached.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
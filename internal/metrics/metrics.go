@@ -0,0 +1,68 @@
+// Package metrics declares the Prometheus collectors exported by the
+// service and registers them with the default registry.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// LookupsTotal counts every passport number looked up, singly or as
+	// part of a batch.
+	LookupsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "amicitizen_lookups_total",
+		Help: "Total passport numbers looked up.",
+	})
+	// BlacklistedTotal counts lookups that matched the blacklist.
+	BlacklistedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "amicitizen_blacklisted_total",
+		Help: "Total lookups that matched the blacklist.",
+	})
+	// LookupDuration observes the latency of each store lookup call; a
+	// batch lookup is observed once for the whole batch.
+	LookupDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "amicitizen_lookup_duration_seconds",
+		Help:    "Latency of blacklist lookups.",
+		Buckets: prometheus.DefBuckets,
+	})
+	// UpdateDuration observes how long a dataset update run takes, from
+	// the conditional GET through ingesting the response.
+	UpdateDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "amicitizen_update_duration_seconds",
+		Help:    "Duration of dataset update runs.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 10),
+	})
+	// UpdateFailuresTotal counts dataset update runs that failed.
+	UpdateFailuresTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "amicitizen_update_failures_total",
+		Help: "Total dataset update failures.",
+	})
+	// RecordsIngestedTotal counts dataset lines read across all
+	// successful updates, blank lines included.
+	RecordsIngestedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "amicitizen_records_ingested_total",
+		Help: "Total records ingested from dataset updates.",
+	})
+	// BytesDownloadedTotal counts bytes read from the remote dataset.
+	BytesDownloadedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "amicitizen_bytes_downloaded_total",
+		Help: "Total bytes downloaded from the remote dataset.",
+	})
+	// UpdaterStatus reports the updater's current status as a gauge
+	// (0=ready, 1=processing, 2=failed).
+	UpdaterStatus = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "amicitizen_updater_status",
+		Help: "Current updater status (0=ready, 1=processing, 2=failed).",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		LookupsTotal,
+		BlacklistedTotal,
+		LookupDuration,
+		UpdateDuration,
+		UpdateFailuresTotal,
+		RecordsIngestedTotal,
+		BytesDownloadedTotal,
+		UpdaterStatus,
+	)
+}
@@ -0,0 +1,394 @@
+// Package updater owns the background job that keeps the blacklist
+// dataset in sync with the remote source: it downloads the dataset on a
+// schedule (or on demand), tracks its own status, and lets callers
+// observe status changes without reaching into its internals.
+package updater
+
+import (
+	"compress/bzip2"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/illabo/amicitizen_serv/internal/metrics"
+)
+
+// Status describes the current state of the updater.
+type Status int
+
+const (
+	Ready Status = iota
+	Processing
+	Failed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Ready:
+		return "ready"
+	case Processing:
+		return "processing"
+	case Failed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Store is the subset of blacklist.Store the updater needs to ingest a
+// dataset and track the remote's cache validators for it.
+type Store interface {
+	PutBatch(ctx context.Context, nums []string) error
+	GetLastUpdated(ctx context.Context) (time.Time, error)
+	SetLastUpdated(ctx context.Context, t time.Time) error
+	GetETag(ctx context.Context) (string, error)
+	SetETag(ctx context.Context, etag string) error
+}
+
+// Event describes a status transition or a progress tick emitted while
+// processing a dataset update.
+type Event struct {
+	Status      Status    `json:"status"`
+	LastUpdated time.Time `json:"last_updated,omitempty"`
+	BytesRead   int64     `json:"bytes_read,omitempty"`
+	RecordsRead int64     `json:"records_read,omitempty"`
+}
+
+// Updater downloads the remote passport blacklist into a Store on a
+// schedule, and on request. Updates run only on the goroutine calling
+// Run; status and listeners are guarded by mu, so Status, Trigger and
+// Subscribe are safe to call concurrently.
+type Updater struct {
+	remote string
+	store  Store
+	period time.Duration
+	logger *slog.Logger
+
+	trigger chan struct{}
+
+	mu        sync.RWMutex
+	status    Status
+	listeners []chan Event
+}
+
+// New creates an Updater that refreshes store from remote every period,
+// logging through logger (slog.Default() if nil).
+func New(remote string, store Store, period time.Duration, logger *slog.Logger) *Updater {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Updater{
+		remote:  remote,
+		store:   store,
+		period:  period,
+		logger:  logger.With(slog.String("component", "updater")),
+		trigger: make(chan struct{}, 1),
+		status:  Ready,
+	}
+}
+
+// Status returns the updater's current status.
+func (u *Updater) Status() Status {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.status
+}
+
+// Trigger requests an out-of-schedule update check. It never blocks; if a
+// check is already queued the request is dropped.
+func (u *Updater) Trigger() {
+	select {
+	case u.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Subscribe registers a channel that receives status transitions and
+// progress ticks. The channel buffers 8 events; an event sent while the
+// buffer is full is dropped for that subscriber. The returned cancel func
+// deregisters it; callers must call it to avoid leaking the channel.
+func (u *Updater) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 8)
+	u.mu.Lock()
+	u.listeners = append(u.listeners, ch)
+	u.mu.Unlock()
+
+	cancel := func() {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		for i, l := range u.listeners {
+			if l == ch {
+				u.listeners = append(u.listeners[:i], u.listeners[i+1:]...)
+				break
+			}
+		}
+	}
+	return ch, cancel
+}
+
+func (u *Updater) setStatus(s Status) {
+	u.mu.Lock()
+	u.status = s
+	u.mu.Unlock()
+	metrics.UpdaterStatus.Set(float64(s))
+	u.broadcast(Event{Status: s})
+}
+
+func (u *Updater) broadcast(e Event) {
+	u.mu.RLock()
+	listeners := make([]chan Event, len(u.listeners))
+	copy(listeners, u.listeners)
+	u.mu.RUnlock()
+
+	for _, l := range listeners {
+		select {
+		case l <- e:
+		default:
+		}
+	}
+}
+
+// Run blocks, performing an update whenever the schedule fires or Trigger
+// is called, until ctx is cancelled.
+func (u *Updater) Run(ctx context.Context) {
+	ticker := time.NewTicker(u.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-u.trigger:
+			u.performUpdate(ctx)
+		case <-ticker.C:
+			u.performUpdate(ctx)
+		}
+	}
+}
+
+func (u *Updater) performUpdate(ctx context.Context) {
+	start := time.Now()
+	changed, err := u.downloadUpdate(ctx)
+	metrics.UpdateDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.UpdateFailuresTotal.Inc()
+		u.logger.Error("dataset download failed", slog.Any("error", err))
+		u.setStatus(Failed)
+		u.Trigger()
+		return
+	}
+	if !changed {
+		return
+	}
+	u.mu.Lock()
+	u.status = Ready
+	u.mu.Unlock()
+	metrics.UpdaterStatus.Set(float64(Ready))
+	lastUpdated, _ := u.store.GetLastUpdated(ctx)
+	u.logger.Info("dataset updated", slog.Time("last_updated", lastUpdated))
+	u.broadcast(Event{Status: Ready, LastUpdated: lastUpdated})
+}
+
+// tempFilePath returns a stable path for the partially-downloaded dataset,
+// so a resumed download can find and extend the file left by a crash.
+func (u *Updater) tempFilePath() string {
+	sum := sha1.Sum([]byte(u.remote))
+	return filepath.Join(os.TempDir(), fmt.Sprintf("amicitizen-serv-%x.bz2.part", sum))
+}
+
+// downloadUpdate issues a conditional GET for the dataset, resuming a
+// previous partial download if one was left behind. It reports whether
+// the dataset changed (false on a 304).
+func (u *Updater) downloadUpdate(ctx context.Context) (bool, error) {
+	etag, err := u.store.GetETag(ctx)
+	if err != nil {
+		return false, err
+	}
+	lastMod, err := u.store.GetLastUpdated(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	tmpPath := u.tempFilePath()
+	var offset int64
+	if fi, err := os.Stat(tmpPath); err == nil {
+		offset = fi.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.remote, nil)
+	if err != nil {
+		return false, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		switch {
+		case etag != "":
+			req.Header.Set("If-Range", etag)
+		case !lastMod.IsZero():
+			req.Header.Set("If-Range", lastMod.Format(http.TimeFormat))
+		}
+	} else {
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if !lastMod.IsZero() {
+			req.Header.Set("If-Modified-Since", lastMod.Format(http.TimeFormat))
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return false, nil
+	case http.StatusPartialContent:
+		// Resuming: keep the offset, append what the server sends next.
+	case http.StatusOK:
+		offset = 0 // server ignored the range (or there was none); start over
+	case http.StatusRequestedRangeNotSatisfiable:
+		os.Remove(tmpPath)
+		return false, fmt.Errorf("remote rejected resume range, will retry fresh next cycle")
+	default:
+		return false, fmt.Errorf("unexpected status fetching dataset: %s", resp.Status)
+	}
+
+	u.setStatus(Processing)
+
+	flags := os.O_CREATE | os.O_WRONLY
+	if resp.StatusCode == http.StatusPartialContent {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(tmpPath, flags, 0644)
+	if err != nil {
+		return false, err
+	}
+	pr := &progressReader{ctx: ctx, r: resp.Body, onRead: u.reportBytesRead}
+	_, copyErr := io.Copy(f, pr)
+	closeErr := f.Close()
+	metrics.BytesDownloadedTotal.Add(float64(pr.total))
+	if copyErr != nil {
+		return false, copyErr
+	}
+	if closeErr != nil {
+		return false, closeErr
+	}
+
+	if err := u.ingest(ctx, tmpPath); err != nil {
+		return false, err
+	}
+	os.Remove(tmpPath)
+
+	newLastMod, err := http.ParseTime(resp.Header.Get("Last-Modified"))
+	if err == nil {
+		if err := u.store.SetLastUpdated(ctx, newLastMod); err != nil {
+			return false, err
+		}
+	}
+	if err := u.store.SetETag(ctx, resp.Header.Get("ETag")); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// progressTickBytes is how many downloaded bytes accumulate between
+// progress events, so a multi-hundred-MB dataset doesn't flood listeners.
+// Record progress is reported separately, once per 1000-record batch.
+const progressTickBytes = 1 << 20 // 1 MiB
+
+// progressReader wraps a reader and calls onRead with the running total
+// of bytes read, so the download loop can emit progress ticks without
+// threading counters through io.Copy. It also aborts the copy once ctx
+// is cancelled, so a shutdown doesn't wait out a slow download.
+type progressReader struct {
+	ctx      context.Context
+	r        io.Reader
+	total    int64
+	reported int64
+	onRead   func(total int64)
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	if err := p.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.r.Read(buf)
+	p.total += int64(n)
+	if p.total-p.reported >= progressTickBytes {
+		p.reported = p.total
+		p.onRead(p.total)
+	}
+	return n, err
+}
+
+func (u *Updater) reportBytesRead(total int64) {
+	u.broadcast(Event{Status: Processing, BytesRead: total})
+}
+
+// ingest decodes the bzip2 dataset at path, one numeric record per line,
+// and loads it into the store in batches.
+func (u *Updater) ingest(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bzip2.NewReader(f)
+	var line string
+	var rdrErr error
+	oneBBuf := make([]byte, 1)
+	lines := []string{}
+	linesRead := 0
+	savedLines := 0
+
+	for {
+		_, rdrErr = reader.Read(oneBBuf)
+		if rdrErr != nil {
+			break
+		}
+		switch {
+		case '0' <= oneBBuf[0] && oneBBuf[0] <= '9':
+			line = line + string(oneBBuf[0])
+		case oneBBuf[0] == '\n':
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+				savedLines++
+			}
+			linesRead++
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if savedLines >= 1000 {
+			if err := u.store.PutBatch(ctx, lines); err != nil {
+				return err
+			}
+			savedLines = 0
+			lines = []string{}
+			u.broadcast(Event{Status: Processing, RecordsRead: int64(linesRead)})
+		}
+	}
+	if len(lines) > 0 {
+		if err := u.store.PutBatch(ctx, lines); err != nil {
+			return err
+		}
+	}
+	if rdrErr != nil && rdrErr != io.EOF {
+		return rdrErr
+	}
+	metrics.RecordsIngestedTotal.Add(float64(linesRead))
+	return nil
+}
@@ -0,0 +1,219 @@
+package updater
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeStore is an in-memory Store for tests.
+type fakeStore struct {
+	lastUpdated time.Time
+	etag        string
+	putBatches  [][]string
+}
+
+func (f *fakeStore) PutBatch(ctx context.Context, nums []string) error {
+	batch := make([]string, len(nums))
+	copy(batch, nums)
+	f.putBatches = append(f.putBatches, batch)
+	return nil
+}
+
+func (f *fakeStore) GetLastUpdated(ctx context.Context) (time.Time, error) {
+	return f.lastUpdated, nil
+}
+
+func (f *fakeStore) SetLastUpdated(ctx context.Context, t time.Time) error {
+	f.lastUpdated = t
+	return nil
+}
+
+func (f *fakeStore) GetETag(ctx context.Context) (string, error) {
+	return f.etag, nil
+}
+
+func (f *fakeStore) SetETag(ctx context.Context, etag string) error {
+	f.etag = etag
+	return nil
+}
+
+func TestUpdaterStatusDefaultsToReady(t *testing.T) {
+	u := New("http://example.invalid/dataset.bz2", &fakeStore{}, time.Hour, nil)
+	if got := u.Status(); got != Ready {
+		t.Fatalf("Status() = %v, want %v", got, Ready)
+	}
+}
+
+func TestUpdaterTriggerDoesNotBlock(t *testing.T) {
+	u := New("http://example.invalid/dataset.bz2", &fakeStore{}, time.Hour, nil)
+	// Trigger has a buffer of one and must never block, even when nothing
+	// is draining it.
+	done := make(chan struct{})
+	go func() {
+		u.Trigger()
+		u.Trigger()
+		u.Trigger()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Trigger blocked")
+	}
+}
+
+func TestUpdaterSubscribeReceivesAndUnsubscribes(t *testing.T) {
+	u := New("http://example.invalid/dataset.bz2", &fakeStore{}, time.Hour, nil)
+
+	ch, cancel := u.Subscribe()
+	u.setStatus(Processing)
+	select {
+	case e := <-ch:
+		if e.Status != Processing {
+			t.Fatalf("event status = %v, want %v", e.Status, Processing)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("did not receive broadcast event")
+	}
+
+	cancel()
+	u.setStatus(Failed)
+	select {
+	case e, ok := <-ch:
+		if ok {
+			t.Fatalf("received event %v after unsubscribing", e)
+		}
+	case <-time.After(100 * time.Millisecond):
+		// No event delivered, as expected; the channel is simply idle
+		// since cancel doesn't close it.
+	}
+}
+
+func TestDownloadUpdateNotModified(t *testing.T) {
+	var gotIfNoneMatch, gotIfModSince string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIfNoneMatch = r.Header.Get("If-None-Match")
+		gotIfModSince = r.Header.Get("If-Modified-Since")
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+
+	lastMod := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := &fakeStore{etag: `"abc123"`, lastUpdated: lastMod}
+	u := New(srv.URL, store, time.Hour, nil)
+	os.Remove(u.tempFilePath())
+
+	changed, err := u.downloadUpdate(context.Background())
+	if err != nil {
+		t.Fatalf("downloadUpdate returned error: %v", err)
+	}
+	if changed {
+		t.Fatalf("downloadUpdate reported changed=true on a 304 response")
+	}
+	if gotIfNoneMatch != `"abc123"` {
+		t.Fatalf("If-None-Match = %q, want %q", gotIfNoneMatch, `"abc123"`)
+	}
+	if gotIfModSince != lastMod.Format(http.TimeFormat) {
+		t.Fatalf("If-Modified-Since = %q, want %q", gotIfModSince, lastMod.Format(http.TimeFormat))
+	}
+}
+
+func TestDownloadUpdateResumesPartialFile(t *testing.T) {
+	var gotRange, gotIfRange string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRange = r.Header.Get("Range")
+		gotIfRange = r.Header.Get("If-Range")
+		// Reporting 304 here short-circuits before ingest runs, which is
+		// all this test needs: proof the resume headers were sent.
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+
+	store := &fakeStore{etag: `"etag-1"`}
+	u := New(srv.URL, store, time.Hour, nil)
+	tmpPath := u.tempFilePath()
+	if err := os.WriteFile(tmpPath, []byte("partial-data"), 0644); err != nil {
+		t.Fatalf("failed to seed partial download file: %v", err)
+	}
+	defer os.Remove(tmpPath)
+
+	if _, err := u.downloadUpdate(context.Background()); err != nil {
+		t.Fatalf("downloadUpdate returned error: %v", err)
+	}
+	if gotRange != "bytes=12-" {
+		t.Fatalf("Range = %q, want %q", gotRange, "bytes=12-")
+	}
+	if gotIfRange != `"etag-1"` {
+		t.Fatalf("If-Range = %q, want %q", gotIfRange, `"etag-1"`)
+	}
+}
+
+// fullBZ2Fixture is "123456789\n987654321\n555555555\n111111111\n" compressed
+// with bzip2. Split across the two halves below, it lets the 206 test
+// exercise a real resumed download without needing compress/bzip2's
+// write-side support, which the standard library doesn't have.
+const fullBZ2FixtureB64 = "QlpoOTFBWSZTWSYBz9oAAAXIAAIQP+AgADEA000DU9DJMOYB0ohBxdXR+Q1k6sqXwu5IpwoSBMA5+0A="
+
+func TestDownloadUpdateResumeIngestsAppendedData(t *testing.T) {
+	full, err := base64.StdEncoding.DecodeString(fullBZ2FixtureB64)
+	if err != nil {
+		t.Fatalf("failed to decode fixture: %v", err)
+	}
+	split := len(full) / 2
+	prefix, suffix := full[:split], full[split:]
+
+	lastMod := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantRange := fmt.Sprintf("bytes=%d-", split)
+		if got := r.Header.Get("Range"); got != wantRange {
+			t.Errorf("Range = %q, want %q", got, wantRange)
+		}
+		w.Header().Set("ETag", `"etag-2"`)
+		w.Header().Set("Last-Modified", lastMod.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(suffix)
+	}))
+	defer srv.Close()
+
+	store := &fakeStore{etag: `"etag-1"`}
+	u := New(srv.URL, store, time.Hour, nil)
+	tmpPath := u.tempFilePath()
+	if err := os.WriteFile(tmpPath, prefix, 0644); err != nil {
+		t.Fatalf("failed to seed partial download file: %v", err)
+	}
+	defer os.Remove(tmpPath)
+
+	changed, err := u.downloadUpdate(context.Background())
+	if err != nil {
+		t.Fatalf("downloadUpdate returned error: %v", err)
+	}
+	if !changed {
+		t.Fatalf("downloadUpdate reported changed=false for a 206 response")
+	}
+	if _, err := os.Stat(tmpPath); !os.IsNotExist(err) {
+		t.Fatalf("partial file was not cleaned up after a successful ingest: err=%v", err)
+	}
+
+	var got []string
+	for _, batch := range store.putBatches {
+		got = append(got, batch...)
+	}
+	want := []string{"123456789", "987654321", "555555555", "111111111"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ingested numbers = %v, want %v", got, want)
+	}
+
+	if store.etag != `"etag-2"` {
+		t.Fatalf("store etag = %q, want %q", store.etag, `"etag-2"`)
+	}
+	if !store.lastUpdated.Equal(lastMod) {
+		t.Fatalf("store lastUpdated = %v, want %v", store.lastUpdated, lastMod)
+	}
+}